@@ -0,0 +1,11 @@
+package bitfinex
+
+// OrderCancelReplaceRequest pairs the ID of an existing order with a full replacement
+// order. Submitting it cancels the original and places the replacement atomically, so
+// the two are guaranteed to be evaluated together rather than as separate round trips.
+type OrderCancelReplaceRequest struct {
+	// CancelID is the ID of the order being cancelled.
+	CancelID int64
+	// New describes the replacement order.
+	New OrderNewRequest
+}
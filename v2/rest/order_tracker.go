@@ -0,0 +1,258 @@
+package rest
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/v2"
+)
+
+// maxTrackedInFlightOrders bounds each of the local in-flight order caches (by order ID,
+// by client order ID, and by cancel target) so a long-running session can't grow any of
+// them without limit. Once a cache reaches the bound, its least recently touched entry
+// is evicted to make room.
+const maxTrackedInFlightOrders = 1024
+
+// InFlightOrder describes an active order the local tracker has observed in an order
+// snapshot and has not yet seen in a terminal state.
+type InFlightOrder struct {
+	OrderID      int64
+	ClientID     int64
+	ClientIDDate string
+	Status       string
+}
+
+type clientOrderKey struct {
+	cid  int64
+	date string
+}
+
+// orderTracker links client order IDs and cancel targets back to the order IDs they
+// belong to, so callers can correlate async notifications with the requests that
+// produced them without re-fetching the full order snapshot on every lookup. It is
+// safe for concurrent use.
+type orderTracker struct {
+	mu sync.RWMutex
+
+	byOrderID  map[int64]*trackedOrder
+	orderLRU   *list.List
+	orderElems map[int64]*list.Element
+
+	byClientID  map[clientOrderKey]int64
+	clientLRU   *list.List
+	clientElems map[clientOrderKey]*list.Element
+
+	byCancelID  map[int64]int64
+	cancelLRU   *list.List
+	cancelElems map[int64]*list.Element
+}
+
+type trackedOrder struct {
+	InFlightOrder
+	order *bitfinex.Order
+}
+
+func newOrderTracker() *orderTracker {
+	return &orderTracker{
+		byOrderID:   make(map[int64]*trackedOrder),
+		orderLRU:    list.New(),
+		orderElems:  make(map[int64]*list.Element),
+		byClientID:  make(map[clientOrderKey]int64),
+		clientLRU:   list.New(),
+		clientElems: make(map[clientOrderKey]*list.Element),
+		byCancelID:  make(map[int64]int64),
+		cancelLRU:   list.New(),
+		cancelElems: make(map[int64]*list.Element),
+	}
+}
+
+// trackPending registers a client order ID against an order that has been submitted but
+// whose numeric order ID isn't known yet. It is resolved the next time the order is
+// observed via observe.
+func (t *orderTracker) trackPending(clientID int64, clientIDDate string) {
+	if clientID == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.setClientIDLocked(clientOrderKey{clientID, clientIDDate}, 0)
+}
+
+// trackCancel links a cancel target (an order ID, or a client order ID resolved to one)
+// back to the order ID it refers to.
+func (t *orderTracker) trackCancel(cancelID, orderID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.byCancelID[cancelID] = orderID
+	t.touchCancelLocked(cancelID)
+	t.enforceCancelBoundLocked()
+}
+
+func (t *orderTracker) resolveCancelTarget(cancelID int64) (int64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	orderID, ok := t.byCancelID[cancelID]
+	return orderID, ok
+}
+
+func (t *orderTracker) resolveClientOrderID(cid int64, cidDate string) (int64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	orderID, ok := t.byClientID[clientOrderKey{cid, cidDate}]
+	return orderID, ok && orderID != 0
+}
+
+// cachedByClientID returns a previously observed order for the given client order ID,
+// without touching the network.
+func (t *orderTracker) cachedByClientID(cid int64, cidDate string) (*bitfinex.Order, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	orderID, ok := t.byClientID[clientOrderKey{cid, cidDate}]
+	if !ok || orderID == 0 {
+		return nil, false
+	}
+	entry, ok := t.byOrderID[orderID]
+	if !ok || entry.order == nil {
+		return nil, false
+	}
+	return entry.order, true
+}
+
+// observe records (or refreshes) an order fetched from the API, evicting it once its
+// status turns terminal.
+func (t *orderTracker) observe(order *bitfinex.Order) {
+	if order == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if isTerminalOrderStatus(order.Status) {
+		t.evictOrderLocked(order.ID)
+		return
+	}
+
+	t.byOrderID[order.ID] = &trackedOrder{
+		InFlightOrder: InFlightOrder{
+			OrderID:      order.ID,
+			ClientID:     order.CID,
+			ClientIDDate: order.CIDDate,
+			Status:       order.Status,
+		},
+		order: order,
+	}
+	if order.CID != 0 {
+		t.setClientIDLocked(clientOrderKey{order.CID, order.CIDDate}, order.ID)
+	}
+	t.touchOrderLocked(order.ID)
+	t.enforceOrderBoundLocked()
+}
+
+func (t *orderTracker) inFlight() []InFlightOrder {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]InFlightOrder, 0, len(t.byOrderID))
+	for _, entry := range t.byOrderID {
+		out = append(out, entry.InFlightOrder)
+	}
+	return out
+}
+
+func (t *orderTracker) setClientIDLocked(key clientOrderKey, orderID int64) {
+	t.byClientID[key] = orderID
+	t.touchClientLocked(key)
+	t.enforceClientBoundLocked()
+}
+
+func (t *orderTracker) touchOrderLocked(orderID int64) {
+	if el, ok := t.orderElems[orderID]; ok {
+		t.orderLRU.MoveToFront(el)
+		return
+	}
+	t.orderElems[orderID] = t.orderLRU.PushFront(orderID)
+}
+
+func (t *orderTracker) touchClientLocked(key clientOrderKey) {
+	if el, ok := t.clientElems[key]; ok {
+		t.clientLRU.MoveToFront(el)
+		return
+	}
+	t.clientElems[key] = t.clientLRU.PushFront(key)
+}
+
+func (t *orderTracker) touchCancelLocked(cancelID int64) {
+	if el, ok := t.cancelElems[cancelID]; ok {
+		t.cancelLRU.MoveToFront(el)
+		return
+	}
+	t.cancelElems[cancelID] = t.cancelLRU.PushFront(cancelID)
+}
+
+// evictOrderLocked removes an order (and any client/cancel entries pointing at it) from
+// every tracked map.
+func (t *orderTracker) evictOrderLocked(orderID int64) {
+	if el, ok := t.orderElems[orderID]; ok {
+		t.orderLRU.Remove(el)
+		delete(t.orderElems, orderID)
+	}
+	if entry, ok := t.byOrderID[orderID]; ok {
+		t.removeClientLocked(clientOrderKey{entry.ClientID, entry.ClientIDDate})
+		delete(t.byOrderID, orderID)
+	}
+	for cancelID, target := range t.byCancelID {
+		if target == orderID {
+			t.removeCancelLocked(cancelID)
+		}
+	}
+}
+
+func (t *orderTracker) removeClientLocked(key clientOrderKey) {
+	if el, ok := t.clientElems[key]; ok {
+		t.clientLRU.Remove(el)
+		delete(t.clientElems, key)
+	}
+	delete(t.byClientID, key)
+}
+
+func (t *orderTracker) removeCancelLocked(cancelID int64) {
+	if el, ok := t.cancelElems[cancelID]; ok {
+		t.cancelLRU.Remove(el)
+		delete(t.cancelElems, cancelID)
+	}
+	delete(t.byCancelID, cancelID)
+}
+
+func (t *orderTracker) enforceOrderBoundLocked() {
+	for len(t.byOrderID) > maxTrackedInFlightOrders {
+		oldest := t.orderLRU.Back()
+		if oldest == nil {
+			return
+		}
+		t.evictOrderLocked(oldest.Value.(int64))
+	}
+}
+
+func (t *orderTracker) enforceClientBoundLocked() {
+	for len(t.byClientID) > maxTrackedInFlightOrders {
+		oldest := t.clientLRU.Back()
+		if oldest == nil {
+			return
+		}
+		t.removeClientLocked(oldest.Value.(clientOrderKey))
+	}
+}
+
+func (t *orderTracker) enforceCancelBoundLocked() {
+	for len(t.byCancelID) > maxTrackedInFlightOrders {
+		oldest := t.cancelLRU.Back()
+		if oldest == nil {
+			return
+		}
+		t.removeCancelLocked(oldest.Value.(int64))
+	}
+}
+
+func isTerminalOrderStatus(status string) bool {
+	return strings.Contains(status, "EXECUTED") || strings.Contains(status, "CANCELED")
+}
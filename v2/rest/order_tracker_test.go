@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/v2"
+)
+
+func TestOrderTrackerObserveAndEvictOnTerminal(t *testing.T) {
+	tr := newOrderTracker()
+	tr.observe(&bitfinex.Order{ID: 1, CID: 100, CIDDate: "2026-07-28", Status: "ACTIVE"})
+
+	if order, ok := tr.cachedByClientID(100, "2026-07-28"); !ok || order.ID != 1 {
+		t.Fatalf("expected cached order 1, got %v, %v", order, ok)
+	}
+
+	tr.observe(&bitfinex.Order{ID: 1, CID: 100, CIDDate: "2026-07-28", Status: "EXECUTED"})
+
+	if _, ok := tr.cachedByClientID(100, "2026-07-28"); ok {
+		t.Fatalf("expected order to be evicted once terminal")
+	}
+}
+
+func TestOrderTrackerResolveCancelTarget(t *testing.T) {
+	tr := newOrderTracker()
+	tr.trackCancel(42, 42)
+
+	orderID, ok := tr.resolveCancelTarget(42)
+	if !ok || orderID != 42 {
+		t.Fatalf("expected cancel 42 to resolve to order 42, got %v, %v", orderID, ok)
+	}
+
+	if _, ok := tr.resolveCancelTarget(7); ok {
+		t.Fatalf("expected untracked cancel ID to be unresolved")
+	}
+}
+
+func TestOrderTrackerEnforcesOrderBound(t *testing.T) {
+	tr := newOrderTracker()
+	for i := 0; i < maxTrackedInFlightOrders+10; i++ {
+		tr.observe(&bitfinex.Order{ID: int64(i), Status: "ACTIVE"})
+	}
+
+	if got := len(tr.byOrderID); got != maxTrackedInFlightOrders {
+		t.Fatalf("expected byOrderID to be capped at %d, got %d", maxTrackedInFlightOrders, got)
+	}
+	if _, ok := tr.byOrderID[0]; ok {
+		t.Fatalf("expected the oldest order to have been evicted")
+	}
+}
+
+func TestOrderTrackerEnforcesClientIDBound(t *testing.T) {
+	tr := newOrderTracker()
+	for i := 0; i < maxTrackedInFlightOrders+10; i++ {
+		tr.trackPending(int64(i+1), fmt.Sprintf("2026-07-%02d", (i%28)+1))
+	}
+
+	if got := len(tr.byClientID); got != maxTrackedInFlightOrders {
+		t.Fatalf("expected byClientID to be capped at %d, got %d", maxTrackedInFlightOrders, got)
+	}
+}
+
+func TestOrderTrackerEnforcesCancelIDBound(t *testing.T) {
+	tr := newOrderTracker()
+	for i := 0; i < maxTrackedInFlightOrders+10; i++ {
+		tr.trackCancel(int64(i), int64(i))
+	}
+
+	if got := len(tr.byCancelID); got != maxTrackedInFlightOrders {
+		t.Fatalf("expected byCancelID to be capped at %d, got %d", maxTrackedInFlightOrders, got)
+	}
+}
@@ -0,0 +1,160 @@
+// Package orderbuilder provides fluent, type-safe builders for bitfinex.OrderNewRequest
+// values, so advanced order types (OCO, trailing-stop, scaled/iceberg ladders) and flags
+// (hidden, post-only, reduce-only) are discoverable without knowing Bitfinex's raw flag
+// bit layout or hand-building meta maps.
+package orderbuilder
+
+import "github.com/bitfinexcom/bitfinex-api-go/v2"
+
+// Order flag bits, as defined by the Bitfinex order flags reference.
+// see https://docs.bitfinex.com/docs/flag-values for more info
+const (
+	FlagOCO        = 16384
+	FlagHidden     = 64
+	FlagClose      = 512
+	FlagReduceOnly = 1024
+	FlagPostOnly   = 4096
+)
+
+// OrderBuilder builds a single bitfinex.OrderNewRequest.
+type OrderBuilder struct {
+	req bitfinex.OrderNewRequest
+}
+
+// New starts building an order of the given type for symbol, with the given amount and
+// price.
+func New(orderType, symbol string, amount, price float64) *OrderBuilder {
+	return &OrderBuilder{req: bitfinex.OrderNewRequest{
+		Type:   orderType,
+		Symbol: symbol,
+		Amount: amount,
+		Price:  price,
+	}}
+}
+
+// GID sets the group order ID the order should be tagged with.
+func (b *OrderBuilder) GID(gid int64) *OrderBuilder {
+	b.req.GID = gid
+	return b
+}
+
+// CID sets the client order ID the order should be tagged with.
+func (b *OrderBuilder) CID(cid int64) *OrderBuilder {
+	b.req.CID = cid
+	return b
+}
+
+// Hidden marks the order as hidden from the public order book.
+func (b *OrderBuilder) Hidden() *OrderBuilder {
+	b.req.Flags |= FlagHidden
+	return b
+}
+
+// PostOnly ensures the order only ever adds liquidity, rejecting it if it would match
+// immediately.
+func (b *OrderBuilder) PostOnly() *OrderBuilder {
+	b.req.Flags |= FlagPostOnly
+	return b
+}
+
+// ReduceOnly ensures the order can only reduce an existing position, never open or
+// increase one.
+func (b *OrderBuilder) ReduceOnly() *OrderBuilder {
+	b.req.Flags |= FlagReduceOnly
+	return b
+}
+
+// Close marks the order to close the position on fill.
+func (b *OrderBuilder) Close() *OrderBuilder {
+	b.req.Flags |= FlagClose
+	return b
+}
+
+// Build returns the constructed order request.
+func (b *OrderBuilder) Build() *bitfinex.OrderNewRequest {
+	req := b.req
+	return &req
+}
+
+// OCOBuilder builds a one-cancels-other order: a primary order that, were its
+// stop-price to be reached instead, automatically cancels in favour of the stop leg
+// Bitfinex submits server-side. This is the FlagOCO + PriceOcoStop order shape, as
+// opposed to two independently submitted orders.
+type OCOBuilder struct {
+	*OrderBuilder
+}
+
+// OCO starts building an OCO order: orderType/symbol/amount/price describe the primary
+// leg, stopPrice is the price of the companion stop-limit leg.
+func OCO(orderType, symbol string, amount, price, stopPrice float64) *OCOBuilder {
+	b := New(orderType, symbol, amount, price)
+	b.req.Flags |= FlagOCO
+	b.req.PriceOcoStop = stopPrice
+	return &OCOBuilder{OrderBuilder: b}
+}
+
+// TrailingStopBuilder builds a trailing-stop order, whose stop price follows the market
+// at a fixed distance rather than sitting at a fixed level.
+type TrailingStopBuilder struct {
+	*OrderBuilder
+}
+
+// TrailingStop starts building a trailing-stop order for symbol and amount, trailing the
+// market by distance.
+func TrailingStop(symbol string, amount, distance float64) *TrailingStopBuilder {
+	b := New("TRAILING STOP", symbol, amount, 0)
+	b.req.PriceTrailing = distance
+	return &TrailingStopBuilder{OrderBuilder: b}
+}
+
+// ScaledOrderSpec describes an iceberg-style ladder of child orders spread evenly across
+// a price range, each for an equal slice of the total amount.
+type ScaledOrderSpec struct {
+	Type        string
+	Symbol      string
+	TotalAmount float64
+	ChildCount  int
+	PriceFrom   float64
+	PriceTo     float64
+	Hidden      bool
+	PostOnly    bool
+}
+
+// ScaledOrderBuilder expands a ScaledOrderSpec into its child order requests.
+type ScaledOrderBuilder struct {
+	spec ScaledOrderSpec
+}
+
+// ScaledOrder starts building a scaled order ladder from spec.
+func ScaledOrder(spec ScaledOrderSpec) *ScaledOrderBuilder {
+	return &ScaledOrderBuilder{spec: spec}
+}
+
+// Build expands the ladder into one order per rung, with prices spread evenly between
+// PriceFrom and PriceTo inclusive. It returns nil if ChildCount is not positive.
+func (b *ScaledOrderBuilder) Build() []*bitfinex.OrderNewRequest {
+	spec := b.spec
+	if spec.ChildCount <= 0 {
+		return nil
+	}
+
+	childAmount := spec.TotalAmount / float64(spec.ChildCount)
+	var step float64
+	if spec.ChildCount > 1 {
+		step = (spec.PriceTo - spec.PriceFrom) / float64(spec.ChildCount-1)
+	}
+
+	orders := make([]*bitfinex.OrderNewRequest, 0, spec.ChildCount)
+	for i := 0; i < spec.ChildCount; i++ {
+		price := spec.PriceFrom + step*float64(i)
+		child := New(spec.Type, spec.Symbol, childAmount, price)
+		if spec.Hidden {
+			child.Hidden()
+		}
+		if spec.PostOnly {
+			child.PostOnly()
+		}
+		orders = append(orders, child.Build())
+	}
+	return orders
+}
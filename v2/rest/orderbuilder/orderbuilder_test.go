@@ -0,0 +1,54 @@
+package orderbuilder
+
+import "testing"
+
+func TestScaledOrderBuilderBuildStepsPricesEvenly(t *testing.T) {
+	spec := ScaledOrderSpec{
+		Type:        "LIMIT",
+		Symbol:      "tBTCUSD",
+		TotalAmount: 1.0,
+		ChildCount:  5,
+		PriceFrom:   100,
+		PriceTo:     200,
+	}
+
+	children := ScaledOrder(spec).Build()
+	if len(children) != spec.ChildCount {
+		t.Fatalf("expected %d child orders, got %d", spec.ChildCount, len(children))
+	}
+
+	wantPrices := []float64{100, 125, 150, 175, 200}
+	for i, child := range children {
+		if child.Price != wantPrices[i] {
+			t.Errorf("child %d price = %v, want %v", i, child.Price, wantPrices[i])
+		}
+		if child.Amount != spec.TotalAmount/float64(spec.ChildCount) {
+			t.Errorf("child %d amount = %v, want %v", i, child.Amount, spec.TotalAmount/float64(spec.ChildCount))
+		}
+	}
+}
+
+func TestScaledOrderBuilderBuildSingleChild(t *testing.T) {
+	spec := ScaledOrderSpec{
+		Type:        "LIMIT",
+		Symbol:      "tBTCUSD",
+		TotalAmount: 1.0,
+		ChildCount:  1,
+		PriceFrom:   100,
+		PriceTo:     200,
+	}
+
+	children := ScaledOrder(spec).Build()
+	if len(children) != 1 {
+		t.Fatalf("expected 1 child order, got %d", len(children))
+	}
+	if children[0].Price != spec.PriceFrom {
+		t.Errorf("single child price = %v, want %v", children[0].Price, spec.PriceFrom)
+	}
+}
+
+func TestScaledOrderBuilderBuildNoChildren(t *testing.T) {
+	if got := ScaledOrder(ScaledOrderSpec{ChildCount: 0}).Build(); got != nil {
+		t.Fatalf("expected nil for a non-positive ChildCount, got %v", got)
+	}
+}
@@ -1,17 +1,38 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/v2"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest/orderbuilder"
 )
 
+// maxOrderMultiOps is the maximum number of operations Bitfinex accepts in a single
+// order/multi request.
+const maxOrderMultiOps = 15
+
 // OrderService manages data flow for the Order API endpoint
 type OrderService struct {
 	requestFactory
 	Synchronous
+
+	trackerOnce sync.Once
+	tracker     *orderTracker
+}
+
+// orderTracker lazily initializes and returns this service's local in-flight order
+// tracker, so OrderService remains usable with its zero value.
+func (s *OrderService) orderTracker() *orderTracker {
+	s.trackerOnce.Do(func() {
+		s.tracker = newOrderTracker()
+	})
+	return s.tracker
 }
 
 type OrderIDs []int
@@ -61,6 +82,42 @@ func (s *OrderService) GetByOrderId(orderID int64) (o *bitfinex.Order, err error
 	return nil, bitfinex.ErrNotFound
 }
 
+// GetByClientOrderId retrieves an order by the client order ID and client order date it
+// was submitted with. A previously observed match is served from the local in-flight
+// order tracker; otherwise it falls back to scanning the active order snapshot, the same
+// way GetByOrderId does.
+func (s *OrderService) GetByClientOrderId(cid int64, cidDate string) (*bitfinex.Order, error) {
+	if order, ok := s.orderTracker().cachedByClientID(cid, cidDate); ok {
+		return order, nil
+	}
+
+	os, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range os.Snapshot {
+		s.orderTracker().observe(order)
+		if order.CID == cid && order.CIDDate == cidDate {
+			return order, nil
+		}
+	}
+	return nil, bitfinex.ErrNotFound
+}
+
+// ResolveCancelTarget returns the order ID a previously submitted cancel request (keyed
+// by order ID or, once resolved, by client order ID) was targeting.
+func (s *OrderService) ResolveCancelTarget(cancelID int64) (int64, bool) {
+	return s.orderTracker().resolveCancelTarget(cancelID)
+}
+
+// TrackedInFlight returns the non-terminal orders the local tracker has observed in the
+// active order snapshots that GetByClientOrderId fetches. An order submitted or cancelled
+// through this OrderService is listed only once such a snapshot has included it, and the
+// snapshot's other active orders are listed as well.
+func (s *OrderService) TrackedInFlight() []InFlightOrder {
+	return s.orderTracker().inFlight()
+}
+
 // Retrieves all past orders
 // See https://docs.bitfinex.com/reference#orders-history for more info
 func (s *OrderService) AllHistory() (*bitfinex.OrderSnapshot, error) {
@@ -158,7 +215,12 @@ func (s *OrderService) SubmitOrder(order *bitfinex.OrderNewRequest) (*bitfinex.N
 	if err != nil {
 		return nil, err
 	}
-	return bitfinex.NewNotificationFromRaw(raw)
+	notification, err := bitfinex.NewNotificationFromRaw(raw)
+	if err != nil {
+		return nil, err
+	}
+	s.orderTracker().trackPending(order.CID, time.Now().Format("2006-01-02"))
+	return notification, nil
 }
 
 // Submit a request to update an order with the given id with the given changes
@@ -194,9 +256,24 @@ func (s *OrderService) SubmitCancelOrder(oc *bitfinex.OrderCancelRequest) error
 	if err != nil {
 		return err
 	}
+	s.trackCancelRequest(oc)
 	return nil
 }
 
+// trackCancelRequest records the order a cancel request targets, resolving it via the
+// client order ID tracker when the request identifies the order by CID rather than ID.
+func (s *OrderService) trackCancelRequest(oc *bitfinex.OrderCancelRequest) {
+	if oc.ID != 0 {
+		s.orderTracker().trackCancel(oc.ID, oc.ID)
+		return
+	}
+	if oc.CID != 0 {
+		if orderID, ok := s.orderTracker().resolveClientOrderID(oc.CID, oc.CIDDate); ok {
+			s.orderTracker().trackCancel(oc.CID, orderID)
+		}
+	}
+}
+
 // CancelOrderMulti cancels multiple orders simultaneously. Orders can be canceled based on the Order ID,
 // the combination of Client Order ID and Client Order Date, or the Group Order ID. Alternatively, the body
 // param 'all' can be used with a value of 1 to cancel all orders.
@@ -255,6 +332,10 @@ func (s *OrderService) CancelOrdersMultiOp(ids OrderIDs) (*bitfinex.Notification
 		return nil, err
 	}
 
+	for _, id := range ids {
+		s.orderTracker().trackCancel(int64(id), int64(id))
+	}
+
 	return bitfinex.NewNotificationFromRaw(raw)
 }
 
@@ -289,6 +370,8 @@ func (s *OrderService) CancelOrderMultiOp(orderID int) (*bitfinex.Notification,
 		return nil, err
 	}
 
+	s.orderTracker().trackCancel(int64(orderID), int64(orderID))
+
 	return bitfinex.NewNotificationFromRaw(raw)
 }
 
@@ -323,6 +406,8 @@ func (s *OrderService) OrderNewMultiOp(order bitfinex.OrderNewRequest) (*bitfine
 		return nil, err
 	}
 
+	s.orderTracker().trackPending(order.CID, time.Now().Format("2006-01-02"))
+
 	return bitfinex.NewNotificationFromRaw(raw)
 }
 
@@ -360,6 +445,274 @@ func (s *OrderService) OrderUpdateMultiOp(order bitfinex.OrderUpdateRequest) (*b
 	return bitfinex.NewNotificationFromRaw(raw)
 }
 
+// BatchRetryOpts configures the retry/backoff behaviour of BatchRetryPlaceOrders.
+type BatchRetryOpts struct {
+	// MaxAttempts is the total number of times a failed order will be submitted,
+	// including the initial attempt.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. Subsequent retries double
+	// this delay, capped at MaxBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff delay between retries.
+	MaxBackoff time.Duration
+}
+
+// SubmitOrders fans a basket of new orders out through the order/multi endpoint in
+// chunks of at most maxOrderMultiOps, so a caller can place more orders than Bitfinex
+// allows in a single request without hand-chunking the calls themselves. It returns the
+// notification for every chunk that was accepted, and the error for every chunk that
+// wasn't; a partial failure still returns the notifications collected so far.
+// see https://docs.bitfinex.com/reference#rest-auth-order-multi for more info
+func (s *OrderService) SubmitOrders(orders []*bitfinex.OrderNewRequest) ([]*bitfinex.Notification, []error) {
+	var notifications []*bitfinex.Notification
+	var errs []error
+
+	for _, chunk := range chunkOrderRequests(orders, maxOrderMultiOps) {
+		ops := make(OrderOps, 0, len(chunk))
+		for _, order := range chunk {
+			ops = append(ops, []interface{}{"on", order.EnrichedPayload()})
+		}
+
+		notification, err := s.OrderMultiOp(ops)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, errs
+}
+
+// BatchRetryPlaceOrders submits orders the same way SubmitOrders does - chunked through
+// order/multi in groups of at most maxOrderMultiOps - but automatically retries the
+// orders that failed with a transient error, backing off exponentially between attempts
+// up to opts.MaxAttempts. Orders that fail with a terminal error are not retried and are
+// reported back in the returned error slice. Bitfinex returns a single notification per
+// order/multi call, so a chunk failure is reported as one error covering every order that
+// chunk contained, and a retried chunk is resubmitted as a whole; failures are never
+// attributed to an individual order within a chunk.
+func (s *OrderService) BatchRetryPlaceOrders(ctx context.Context, orders []*bitfinex.OrderNewRequest, opts BatchRetryOpts) ([]*bitfinex.Notification, []error) {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Second
+	}
+
+	var notifications []*bitfinex.Notification
+	var errs []error
+	pending := orders
+	backoff := opts.InitialBackoff
+
+	for attempt := 1; len(pending) > 0 && attempt <= opts.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				errs = append(errs, ctx.Err())
+				return notifications, errs
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > opts.MaxBackoff {
+				backoff = opts.MaxBackoff
+			}
+		}
+
+		accepted, failed := s.submitOrderChunks(pending)
+		notifications = append(notifications, accepted...)
+
+		var roundErrs []error
+		pending, roundErrs = nextPendingRetryOrders(failed, attempt, opts.MaxAttempts)
+		errs = append(errs, roundErrs...)
+	}
+
+	return notifications, errs
+}
+
+// failedChunk groups the orders sent in one order/multi request that failed together.
+type failedChunk struct {
+	orders []*bitfinex.OrderNewRequest
+	err    error
+}
+
+// nextPendingRetryOrders sorts a round's failed chunks into orders worth retrying and
+// errors to report back, returning a freshly allocated pending slice on every call so
+// BatchRetryPlaceOrders never writes into the backing array of the orders slice its
+// caller passed in.
+func nextPendingRetryOrders(failed []failedChunk, attempt, maxAttempts int) ([]*bitfinex.OrderNewRequest, []error) {
+	var pending []*bitfinex.OrderNewRequest
+	var errs []error
+	for _, f := range failed {
+		if attempt == maxAttempts || !isTransientOrderErr(f.err) {
+			errs = append(errs, f.err)
+			continue
+		}
+		pending = append(pending, f.orders...)
+	}
+	return pending, errs
+}
+
+// submitOrderChunks submits orders through order/multi in chunks of at most
+// maxOrderMultiOps, the same way SubmitOrders does.
+func (s *OrderService) submitOrderChunks(orders []*bitfinex.OrderNewRequest) ([]*bitfinex.Notification, []failedChunk) {
+	var notifications []*bitfinex.Notification
+	var failed []failedChunk
+
+	for _, chunk := range chunkOrderRequests(orders, maxOrderMultiOps) {
+		ops := make(OrderOps, 0, len(chunk))
+		for _, order := range chunk {
+			ops = append(ops, []interface{}{"on", order.EnrichedPayload()})
+		}
+
+		notification, err := s.OrderMultiOp(ops)
+		if err == nil && notification != nil && notification.Status == "ERROR" {
+			err = fmt.Errorf("order rejected: %s", notification.Text)
+		}
+		if err != nil {
+			failed = append(failed, failedChunk{orders: chunk, err: err})
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, failed
+}
+
+// isTransientOrderErr reports whether err looks like a transient failure (rate limiting,
+// nonce errors, timeouts) worth retrying, as opposed to a terminal rejection of the order
+// itself (e.g. insufficient balance, invalid parameters).
+func isTransientOrderErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.NewReplacer("_", " ", "-", " ").Replace(strings.ToLower(err.Error()))
+	for _, substr := range []string{"rate limit", "nonce", "timeout", "temporarily"} {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransientNotification reports whether a rejected notification's status/text indicate
+// a transient failure rather than a terminal rejection of the order.
+func isTransientNotification(n *bitfinex.Notification) bool {
+	if n == nil || n.Status != "ERROR" {
+		return false
+	}
+	return isTransientOrderErr(fmt.Errorf("%s", n.Text))
+}
+
+func chunkOrderRequests(orders []*bitfinex.OrderNewRequest, size int) [][]*bitfinex.OrderNewRequest {
+	if len(orders) == 0 {
+		return nil
+	}
+	var chunks [][]*bitfinex.OrderNewRequest
+	for size < len(orders) {
+		orders, chunks = orders[size:], append(chunks, orders[0:size:size])
+	}
+	return append(chunks, orders)
+}
+
+// DeadManSwitchConn is the subset of the v2 websocket client's behaviour OrderService
+// needs to arm and disarm Bitfinex's Dead Man's Switch; satisfied by *websocket.Client.
+// Dead Man's Switch is only exposed over the authenticated websocket connection's `conf`
+// command (flag 0x4) - there is no REST equivalent.
+type DeadManSwitchConn interface {
+	SetDeadManSwitch(enabled bool) error
+}
+
+// SetDeadManSwitch arms Bitfinex's Dead Man's Switch on conn: if the connection drops or
+// misses a heartbeat, the server cancels every open order on the account. The call is
+// idempotent, so it is safe to invoke repeatedly to keep the timer re-armed; see
+// KeepDeadManSwitchArmed for a helper that does this automatically.
+// see https://docs.bitfinex.com/reference#ws-auth-input-conf for more info
+func (s *OrderService) SetDeadManSwitch(conn DeadManSwitchConn) error {
+	return conn.SetDeadManSwitch(true)
+}
+
+// DisableDeadManSwitch disarms the Dead Man's Switch previously armed by
+// SetDeadManSwitch.
+func (s *OrderService) DisableDeadManSwitch(conn DeadManSwitchConn) error {
+	return conn.SetDeadManSwitch(false)
+}
+
+// KeepDeadManSwitchArmed starts a background goroutine that re-arms the Dead Man's Switch
+// on conn every interval, so the timer never lapses while a strategy is running
+// unattended. The goroutine stops when ctx is cancelled; cancellation alone does not
+// disarm the switch, so call DisableDeadManSwitch afterwards for a clean teardown.
+func (s *OrderService) KeepDeadManSwitchArmed(ctx context.Context, conn DeadManSwitchConn, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = s.SetDeadManSwitch(conn)
+			}
+		}
+	}()
+}
+
+// SubmitCancelReplaceOrder atomically replaces an existing order: it sends the cancel
+// ("oc") and the new order ("on") as a single order/multi request so Bitfinex evaluates
+// both together, rather than risking the new order being skipped after an independent
+// cancel succeeds (or vice versa).
+// see https://docs.bitfinex.com/reference#rest-auth-order-multi for more info
+func (s *OrderService) SubmitCancelReplaceOrder(cr *bitfinex.OrderCancelReplaceRequest) (*bitfinex.Notification, error) {
+	ops := OrderOps{
+		{
+			"oc",
+			map[string]int64{"id": cr.CancelID},
+		},
+		{
+			"on",
+			cr.New.EnrichedPayload(),
+		},
+	}
+
+	notification, err := s.OrderMultiOp(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	s.orderTracker().trackCancel(cr.CancelID, cr.CancelID)
+	s.orderTracker().trackPending(cr.New.CID, time.Now().Format("2006-01-02"))
+
+	return notification, nil
+}
+
+// SubmitScaledOrder expands a scaled (iceberg-ladder) order spec into its child orders
+// and submits the whole ladder as a single order/multi request, so the rungs are placed
+// together rather than as N independent round trips.
+// see https://docs.bitfinex.com/reference#rest-auth-order-multi for more info
+func (s *OrderService) SubmitScaledOrder(spec orderbuilder.ScaledOrderSpec) (*bitfinex.Notification, error) {
+	children := orderbuilder.ScaledOrder(spec).Build()
+
+	ops := make(OrderOps, 0, len(children))
+	for _, child := range children {
+		ops = append(ops, []interface{}{"on", child.EnrichedPayload()})
+	}
+
+	notification, err := s.OrderMultiOp(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	cidDate := time.Now().Format("2006-01-02")
+	for _, child := range children {
+		s.orderTracker().trackPending(child.CID, cidDate)
+	}
+
+	return notification, nil
+}
+
 // OrderMultiOp - send Multiple order-related operations. Please note the sent object has
 // only one property with a value of a slice of slices detailing each order operation.
 // see https://docs.bitfinex.com/reference#rest-auth-order-multi for more info
@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/v2"
+)
+
+func TestChunkOrderRequestsEmpty(t *testing.T) {
+	chunks := chunkOrderRequests(nil, maxOrderMultiOps)
+	if chunks != nil {
+		t.Fatalf("expected no chunks for an empty input, got %v", chunks)
+	}
+}
+
+func TestChunkOrderRequestsRespectsSize(t *testing.T) {
+	orders := make([]*bitfinex.OrderNewRequest, 37)
+	for i := range orders {
+		orders[i] = &bitfinex.OrderNewRequest{}
+	}
+
+	chunks := chunkOrderRequests(orders, maxOrderMultiOps)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks for 37 orders at size %d, got %d", maxOrderMultiOps, len(chunks))
+	}
+
+	var total int
+	for i, chunk := range chunks {
+		if len(chunk) > maxOrderMultiOps {
+			t.Fatalf("chunk %d has %d orders, exceeds max of %d", i, len(chunk), maxOrderMultiOps)
+		}
+		total += len(chunk)
+	}
+	if total != len(orders) {
+		t.Fatalf("expected chunks to cover all %d orders, got %d", len(orders), total)
+	}
+}
+
+func TestNextPendingRetryOrdersDoesNotAliasInput(t *testing.T) {
+	orders := make([]*bitfinex.OrderNewRequest, 37)
+	for i := range orders {
+		orders[i] = &bitfinex.OrderNewRequest{CID: int64(i)}
+	}
+	original := append([]*bitfinex.OrderNewRequest(nil), orders...)
+
+	chunks := chunkOrderRequests(orders, maxOrderMultiOps)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	failed := []failedChunk{
+		{orders: chunks[1], err: errors.New("nonce too small")},
+		{orders: chunks[2], err: errors.New("nonce too small")},
+	}
+
+	pending, errs := nextPendingRetryOrders(failed, 1, 2)
+	if len(errs) != 0 {
+		t.Fatalf("expected no terminal errors on a retryable attempt, got %v", errs)
+	}
+	if len(pending) != len(chunks[1])+len(chunks[2]) {
+		t.Fatalf("expected %d pending orders, got %d", len(chunks[1])+len(chunks[2]), len(pending))
+	}
+
+	// Mutating the returned pending slice must never reach back into the caller's
+	// original orders slice.
+	for i := range pending {
+		pending[i] = &bitfinex.OrderNewRequest{CID: -1}
+	}
+	for i, order := range orders {
+		if order.CID != original[i].CID {
+			t.Fatalf("orders[%d] was mutated: got CID %d, want %d", i, order.CID, original[i].CID)
+		}
+	}
+}
+
+func TestIsTransientOrderErr(t *testing.T) {
+	cases := []struct {
+		err       error
+		transient bool
+	}{
+		{nil, false},
+		{errors.New("insufficient balance"), false},
+		{errors.New("ERR_RATE_LIMIT"), true},
+		{errors.New("nonce too small"), true},
+		{errors.New("request timeout"), true},
+		{errors.New("service temporarily unavailable"), true},
+	}
+
+	for _, c := range cases {
+		if got := isTransientOrderErr(c.err); got != c.transient {
+			t.Errorf("isTransientOrderErr(%v) = %v, want %v", c.err, got, c.transient)
+		}
+	}
+}
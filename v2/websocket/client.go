@@ -0,0 +1,42 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/gorilla/websocket"
+)
+
+// publicWebSocketURL is the Bitfinex v2 websocket endpoint used for both public market
+// data and, once authenticated via the "auth" command, private account streams.
+const publicWebSocketURL = "wss://api-pub.bitfinex.com/ws/2"
+
+// Client is a single connection to Bitfinex's v2 websocket API. It owns the underlying
+// transport; the only command it sends is "conf" (see SetDeadManSwitch). It does not
+// send "auth" or "subscribe", so it never authenticates the connection itself.
+type Client struct {
+	conn *websocket.Conn
+}
+
+// NewClient dials the Bitfinex v2 websocket API and returns a Client wrapping the
+// resulting connection. Callers are responsible for closing the connection when done.
+func NewClient() (*Client, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(publicWebSocketURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// send writes v to the connection as a single JSON-encoded websocket text frame.
+func (c *Client) send(v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, payload)
+}
@@ -0,0 +1,26 @@
+package websocket
+
+// confFlag is a bitmask of behaviours requested via the authenticated "conf" websocket
+// command.
+// see https://docs.bitfinex.com/docs/ws-general#configuration for more info
+type confFlag int
+
+// confFlagDeadManSwitch arms Bitfinex's Dead Man's Switch: if the connection drops or
+// misses a heartbeat, the server cancels every open order on the account.
+const confFlagDeadManSwitch confFlag = 0x4
+
+type confRequest struct {
+	Event string   `json:"event"`
+	Flags confFlag `json:"flags"`
+}
+
+// SetDeadManSwitch arms (enabled=true) or disarms (enabled=false) Bitfinex's Dead Man's
+// Switch for this connection by sending the authenticated "conf" command with flag 0x4.
+// see https://docs.bitfinex.com/reference#ws-auth-input-conf for more info
+func (c *Client) SetDeadManSwitch(enabled bool) error {
+	var flags confFlag
+	if enabled {
+		flags = confFlagDeadManSwitch
+	}
+	return c.send(confRequest{Event: "conf", Flags: flags})
+}